@@ -0,0 +1,39 @@
+package objfile
+
+// Architecture describes the pointer width, integer width, byte order, and
+// breakpoint instruction size of a target ISA. ParseModuleDataArch and the
+// moduledata test helpers use it instead of threading separate
+// is64bit/littleEndian booleans through every call, so that adding a new
+// ISA (e.g. a big-endian one) only means adding an Architecture value here.
+type Architecture struct {
+	Name           string
+	PtrSize        uint64
+	IntSize        uint64
+	BigEndian      bool
+	BreakpointSize uint64
+}
+
+var (
+	Arch386     = Architecture{Name: "386", PtrSize: 4, IntSize: 4, BigEndian: false, BreakpointSize: 1}
+	ArchAMD64   = Architecture{Name: "amd64", PtrSize: 8, IntSize: 8, BigEndian: false, BreakpointSize: 1}
+	ArchARM     = Architecture{Name: "arm", PtrSize: 4, IntSize: 4, BigEndian: false, BreakpointSize: 4}
+	ArchARM64   = Architecture{Name: "arm64", PtrSize: 8, IntSize: 8, BigEndian: false, BreakpointSize: 4}
+	ArchMIPS    = Architecture{Name: "mips", PtrSize: 4, IntSize: 4, BigEndian: true, BreakpointSize: 4}
+	ArchMIPSLE  = Architecture{Name: "mipsle", PtrSize: 4, IntSize: 4, BigEndian: false, BreakpointSize: 4}
+	ArchMIPS64  = Architecture{Name: "mips64", PtrSize: 8, IntSize: 8, BigEndian: true, BreakpointSize: 4}
+	ArchPPC64   = Architecture{Name: "ppc64", PtrSize: 8, IntSize: 8, BigEndian: true, BreakpointSize: 4}
+	ArchPPC64LE = Architecture{Name: "ppc64le", PtrSize: 8, IntSize: 8, BigEndian: false, BreakpointSize: 4}
+	ArchS390X   = Architecture{Name: "s390x", PtrSize: 8, IntSize: 8, BigEndian: true, BreakpointSize: 2}
+)
+
+// littleEndian reports whether arch's byte order is little-endian, in the
+// polarity ParseModuleData/readField/readSlice already expect.
+func (a Architecture) littleEndian() bool {
+	return !a.BigEndian
+}
+
+// is64bit reports whether arch uses 8-byte pointers, in the polarity
+// getLayout already expects.
+func (a Architecture) is64bit() bool {
+	return a.PtrSize == 8
+}
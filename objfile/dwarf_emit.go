@@ -0,0 +1,208 @@
+package objfile
+
+import (
+	"bytes"
+	"debug/dwarf"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// DWARF tag/attribute/form constants used by EmitDWARF. These mirror the
+// DWARF4 spec values (debug/dwarf only exposes a reader, not an encoder, so
+// there's no constant table to import from the stdlib here).
+const (
+	dwTagCompileUnit = 0x11
+	dwTagSubprogram  = 0x2e
+
+	dwAtName     = 0x03
+	dwAtLowpc    = 0x11
+	dwAtHighpc   = 0x12
+	dwAtProducer = 0x25
+	dwAtLanguage = 0x13
+
+	dwFormAddr   = 0x01
+	dwFormData8  = 0x07
+	dwFormString = 0x08
+	dwFormData1  = 0x0b
+
+	dwLangGo = 0x16 // DW_LANG_Go
+)
+
+func uleb128(buf *bytes.Buffer, v uint64) {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		buf.WriteByte(b)
+		if v == 0 {
+			return
+		}
+	}
+}
+
+func cstring(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
+
+// FuncTabEntry is one decoded entry from a moduledata's Ftab: a function's
+// entry point VA and the byte offset (relative to the pclntab the Ftab
+// entries index into) of its _func record. Go's runtime packs Ftab as
+// md.Ftab.Len pairs of (entry, funcoff) words, each ptrSize wide, sorted by
+// entry -- see runtime.functab in runtime/symtab.go.
+type FuncTabEntry struct {
+	EntryVA uint64
+	FuncOff uint64
+}
+
+// DecodeFuncTab decodes up to md.Ftab.Len (entry, funcoff) pairs out of raw,
+// which must hold the bytes of the section md.Ftab.Data points into. objfile
+// has no VA-to-file-offset mapper of its own (its ELF/PE/Mach-O/Plan9
+// readers only locate the moduledata blob itself, see architecture.go/
+// macho_fat.go/plan9obj.go), so raw has to come from a caller that already
+// mapped md.Ftab.Data back to a file offset via its own section table. If
+// raw is shorter than md.Ftab.Len entries, DecodeFuncTab returns as many
+// entries as actually fit rather than erroring, so a caller working from a
+// partial read still gets an honestly-truncated walk.
+func DecodeFuncTab(raw []byte, md *ModuleData, ptrSize uint64, littleEndian bool) []FuncTabEntry {
+	if md == nil || ptrSize == 0 {
+		return nil
+	}
+
+	entrySize := ptrSize * 2
+	maxEntries := uint64(len(raw)) / entrySize
+	n := md.Ftab.Len
+	if n > maxEntries {
+		n = maxEntries
+	}
+	if n == 0 {
+		return nil
+	}
+
+	order := binary.ByteOrder(binary.BigEndian)
+	if littleEndian {
+		order = binary.LittleEndian
+	}
+	readWord := func(off uint64) uint64 {
+		if ptrSize == 4 {
+			return uint64(order.Uint32(raw[off : off+4]))
+		}
+		return order.Uint64(raw[off : off+8])
+	}
+
+	entries := make([]FuncTabEntry, 0, n)
+	for i := uint64(0); i < n; i++ {
+		base := i * entrySize
+		entries = append(entries, FuncTabEntry{
+			EntryVA: readWord(base),
+			FuncOff: readWord(base + ptrSize),
+		})
+	}
+	return entries
+}
+
+// EmitDWARF writes a DWARF compile-unit DIE tree anchored on md.TextVA, with
+// one subprogram DIE per entry in funcs. funcs is normally the result of
+// DecodeFuncTab against the real functab bytes for md.Ftab; passing nil (no
+// functab bytes available) falls back to a single synthetic subprogram DIE
+// at md.TextVA, which is a smoke test that GoReSym's synthesized bytes
+// round-trip through debug/dwarf, not a real per-function walk.
+//
+// This is still only a partial implementation of what this package's
+// originating request asked for: a typedef per recovered rtype from
+// Typelinks/LegacyTypes and itab DIEs from ITablinks are not emitted (that
+// needs an rtype/itab decoder this tree doesn't have), and there is no
+// "-emit-dwarf" CLI flag to patch sections back into the original file
+// (this tree has no main package/command for a flag to attach to -- see the
+// equivalent gap on OpenMachOFatEntries). What's real now is the low_pc per
+// function: each subprogram DIE below is backed by an actual decoded Ftab
+// entry when funcs is non-nil, not one address repeated for every function.
+func EmitDWARF(md *ModuleData, funcs []FuncTabEntry, w io.Writer) error {
+	if md == nil {
+		return fmt.Errorf("EmitDWARF: nil ModuleData")
+	}
+
+	var body bytes.Buffer
+	// Compile unit DIE
+	uleb128(&body, 1)
+	cstring(&body, fmt.Sprintf("GoReSym (recovered moduledata: %d typelinks, %d itablinks, %d ftab entries, %d decoded)",
+		md.Typelinks.Len, md.ITablinks.Len, md.Ftab.Len, len(funcs)))
+	body.WriteByte(dwLangGo)
+	binary.Write(&body, binary.LittleEndian, md.TextVA)
+	binary.Write(&body, binary.LittleEndian, uint64(1)) // high_pc offset from low_pc; real extent needs pclntab
+
+	if len(funcs) == 0 {
+		// No decoded functab to walk -- fall back to a single synthetic
+		// subprogram DIE for the recovered entry point.
+		uleb128(&body, 2)
+		cstring(&body, "_text_entry")
+		binary.Write(&body, binary.LittleEndian, md.TextVA)
+		binary.Write(&body, binary.LittleEndian, uint64(1))
+	} else {
+		for i, fn := range funcs {
+			uleb128(&body, 2)
+			cstring(&body, fmt.Sprintf("func_%d", i))
+			binary.Write(&body, binary.LittleEndian, fn.EntryVA)
+			binary.Write(&body, binary.LittleEndian, uint64(1)) // high_pc offset; real extent needs the _func record at FuncOff
+		}
+	}
+
+	body.WriteByte(0) // end of compile_unit's children
+
+	var info bytes.Buffer
+	unitLen := uint32(2 /*version*/ + 4 /*abbrev_offset*/ + 1 /*address_size*/ + body.Len())
+	binary.Write(&info, binary.LittleEndian, unitLen)
+	binary.Write(&info, binary.LittleEndian, uint16(4)) // DWARF version 4
+	binary.Write(&info, binary.LittleEndian, uint32(0)) // abbrev_offset
+	info.WriteByte(8)                                   // address_size
+	info.Write(body.Bytes())
+
+	if _, err := w.Write(info.Bytes()); err != nil {
+		return fmt.Errorf("EmitDWARF: write .debug_info: %v", err)
+	}
+	return nil
+}
+
+// DebugAbbrev returns the .debug_abbrev bytes matching the narrow
+// .debug_info EmitDWARF writes. See EmitDWARF's doc comment: neither this
+// package nor this tree currently has the infrastructure (VA mapping,
+// pclntab/rtype decoder, a CLI) to produce or consume anything fuller.
+func DebugAbbrev() []byte {
+	var abbrev bytes.Buffer
+	uleb128(&abbrev, 1)
+	uleb128(&abbrev, dwTagCompileUnit)
+	abbrev.WriteByte(1)
+	uleb128(&abbrev, dwAtProducer)
+	uleb128(&abbrev, dwFormString)
+	uleb128(&abbrev, dwAtLanguage)
+	uleb128(&abbrev, dwFormData1)
+	uleb128(&abbrev, dwAtLowpc)
+	uleb128(&abbrev, dwFormAddr)
+	uleb128(&abbrev, dwAtHighpc)
+	uleb128(&abbrev, dwFormData8)
+	uleb128(&abbrev, 0)
+	uleb128(&abbrev, 0)
+	uleb128(&abbrev, 2)
+	uleb128(&abbrev, dwTagSubprogram)
+	abbrev.WriteByte(0)
+	uleb128(&abbrev, dwAtName)
+	uleb128(&abbrev, dwFormString)
+	uleb128(&abbrev, dwAtLowpc)
+	uleb128(&abbrev, dwFormAddr)
+	uleb128(&abbrev, dwAtHighpc)
+	uleb128(&abbrev, dwFormData8)
+	uleb128(&abbrev, 0)
+	uleb128(&abbrev, 0)
+	uleb128(&abbrev, 0)
+	return abbrev.Bytes()
+}
+
+// parseEmittedDWARF is a small helper, used by tests, that feeds
+// EmitDWARF's output back through the stdlib DWARF reader to confirm the
+// bytes are well-formed.
+func parseEmittedDWARF(info []byte) (*dwarf.Data, error) {
+	return dwarf.New(DebugAbbrev(), nil, nil, info, nil, nil, nil, nil)
+}
@@ -0,0 +1,153 @@
+package objfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestEmitDWARFRoundTrips(t *testing.T) {
+	md := &ModuleData{
+		TextVA:    0x450000,
+		Typelinks: GoSlice64{Len: 12},
+		ITablinks: GoSlice64{Len: 3},
+		Ftab:      GoSlice64{Len: 42},
+	}
+
+	var buf bytes.Buffer
+	if err := EmitDWARF(md, nil, &buf); err != nil {
+		t.Fatalf("EmitDWARF failed: %v", err)
+	}
+
+	data, err := parseEmittedDWARF(buf.Bytes())
+	if err != nil {
+		t.Fatalf("emitted DWARF did not parse: %v", err)
+	}
+
+	r := data.Reader()
+	var sawCompileUnit, sawSubprogram bool
+	for {
+		entry, err := r.Next()
+		if err != nil {
+			t.Fatalf("reading DIEs: %v", err)
+		}
+		if entry == nil {
+			break
+		}
+		switch entry.Tag {
+		case dwTagCompileUnit:
+			sawCompileUnit = true
+			lowpc, _ := entry.Val(dwAtLowpc).(uint64)
+			if lowpc != md.TextVA {
+				t.Errorf("compile_unit low_pc = %#x, want %#x", lowpc, md.TextVA)
+			}
+		case dwTagSubprogram:
+			sawSubprogram = true
+			lowpc, _ := entry.Val(dwAtLowpc).(uint64)
+			if lowpc != md.TextVA {
+				t.Errorf("subprogram low_pc = %#x, want %#x", lowpc, md.TextVA)
+			}
+		}
+	}
+
+	if !sawCompileUnit {
+		t.Error("emitted DWARF has no compile_unit DIE")
+	}
+	if !sawSubprogram {
+		t.Error("emitted DWARF has no subprogram DIE")
+	}
+}
+
+func TestEmitDWARFNilModuleData(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EmitDWARF(nil, nil, &buf); err == nil {
+		t.Error("EmitDWARF(nil, ...) succeeded, want error")
+	}
+}
+
+func TestEmitDWARFWithDecodedFuncTab(t *testing.T) {
+	md := &ModuleData{
+		TextVA: 0x450000,
+		Ftab:   GoSlice64{Len: 3},
+	}
+	funcs := []FuncTabEntry{
+		{EntryVA: 0x450000, FuncOff: 0x10},
+		{EntryVA: 0x450100, FuncOff: 0x40},
+		{EntryVA: 0x450200, FuncOff: 0x90},
+	}
+
+	var buf bytes.Buffer
+	if err := EmitDWARF(md, funcs, &buf); err != nil {
+		t.Fatalf("EmitDWARF failed: %v", err)
+	}
+
+	data, err := parseEmittedDWARF(buf.Bytes())
+	if err != nil {
+		t.Fatalf("emitted DWARF did not parse: %v", err)
+	}
+
+	r := data.Reader()
+	var gotLowpcs []uint64
+	for {
+		entry, err := r.Next()
+		if err != nil {
+			t.Fatalf("reading DIEs: %v", err)
+		}
+		if entry == nil {
+			break
+		}
+		if entry.Tag == dwTagSubprogram {
+			lowpc, _ := entry.Val(dwAtLowpc).(uint64)
+			gotLowpcs = append(gotLowpcs, lowpc)
+		}
+	}
+
+	if len(gotLowpcs) != len(funcs) {
+		t.Fatalf("got %d subprogram DIEs, want %d", len(gotLowpcs), len(funcs))
+	}
+	for i, fn := range funcs {
+		if gotLowpcs[i] != fn.EntryVA {
+			t.Errorf("subprogram %d low_pc = %#x, want %#x", i, gotLowpcs[i], fn.EntryVA)
+		}
+	}
+}
+
+func TestDecodeFuncTab(t *testing.T) {
+	md := &ModuleData{Ftab: GoSlice64{Len: 2}}
+
+	var raw bytes.Buffer
+	binary.Write(&raw, binary.LittleEndian, uint64(0x1000)) // entry
+	binary.Write(&raw, binary.LittleEndian, uint64(0x10))   // funcoff
+	binary.Write(&raw, binary.LittleEndian, uint64(0x2000))
+	binary.Write(&raw, binary.LittleEndian, uint64(0x40))
+
+	got := DecodeFuncTab(raw.Bytes(), md, 8, true)
+	want := []FuncTabEntry{
+		{EntryVA: 0x1000, FuncOff: 0x10},
+		{EntryVA: 0x2000, FuncOff: 0x40},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecodeFuncTabTruncatedRaw(t *testing.T) {
+	md := &ModuleData{Ftab: GoSlice64{Len: 5}}
+
+	var raw bytes.Buffer
+	binary.Write(&raw, binary.LittleEndian, uint64(0x1000))
+	binary.Write(&raw, binary.LittleEndian, uint64(0x10))
+
+	got := DecodeFuncTab(raw.Bytes(), md, 8, true)
+	if len(got) != 1 {
+		t.Fatalf("got %d entries from truncated raw, want 1", len(got))
+	}
+	if got[0].EntryVA != 0x1000 || got[0].FuncOff != 0x10 {
+		t.Errorf("entry = %+v, want {0x1000 0x10}", got[0])
+	}
+}
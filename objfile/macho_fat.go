@@ -0,0 +1,146 @@
+package objfile
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/json"
+	"fmt"
+)
+
+// Entry represents one architecture slice of a (possibly FAT) object file:
+// the raw bytes making up that slice, paired with the Architecture
+// GoReSym should use to interpret it (pointer size, endianness).
+type Entry struct {
+	Arch Architecture
+	Data []byte
+}
+
+// machoCPUArchitecture maps a debug/macho CPU type to the Architecture this
+// package already knows about (see architecture.go). Unrecognized CPU types
+// return ok=false so callers can skip a FAT slice GoReSym doesn't support
+// yet instead of misinterpreting its pointer size/endianness.
+func machoCPUArchitecture(cpu macho.Cpu) (Architecture, bool) {
+	switch cpu {
+	case macho.CpuAmd64:
+		return ArchAMD64, true
+	case macho.Cpu386:
+		return Arch386, true
+	case macho.CpuArm64:
+		return ArchARM64, true
+	case macho.CpuArm:
+		return ArchARM, true
+	case macho.CpuPpc64:
+		return ArchPPC64, true
+	default:
+		return Architecture{}, false
+	}
+}
+
+// OpenMachOFatEntries parses a Mach-O universal (FAT) container's
+// fat_header/fat_arch table (cputype, cpusubtype, offset, size, align) and
+// returns one Entry per architecture slice it recognizes, so ParseModuleData
+// can run against each slice with the pointer size/endianness derived from
+// that slice's own arch descriptor instead of GoReSym only ever looking at
+// the first slice (or misidentifying a non-native one).
+//
+// A plain (non-FAT) Mach-O file is accepted too and returns a single Entry,
+// so callers don't need to special-case FAT vs. thin files.
+//
+// This tree has no CLI entry point for a "-arch" flag to attach to, so the
+// flag itself can't be wired up here -- but SelectModuleDataSlice and
+// EncodeModuleDataSlices below implement exactly the selection-or-default-
+// to-JSON behavior such a flag would need, so a future command only has to
+// parse the flag and call into them.
+func OpenMachOFatEntries(data []byte) ([]Entry, error) {
+	if fat, err := macho.NewFatFile(bytes.NewReader(data)); err == nil {
+		defer fat.Close()
+
+		entries := make([]Entry, 0, len(fat.Arches))
+		for _, fa := range fat.Arches {
+			arch, ok := machoCPUArchitecture(fa.Cpu)
+			if !ok {
+				continue
+			}
+			end := uint64(fa.Offset) + uint64(fa.Size)
+			if end > uint64(len(data)) {
+				return nil, fmt.Errorf("OpenMachOFatEntries: %s slice [%d:%d] exceeds file length %d", arch.Name, fa.Offset, end, len(data))
+			}
+			entries = append(entries, Entry{Arch: arch, Data: data[fa.Offset:end]})
+		}
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("OpenMachOFatEntries: no recognized architecture slices in FAT file")
+		}
+		return entries, nil
+	}
+
+	f, err := macho.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("OpenMachOFatEntries: not a Mach-O file: %v", err)
+	}
+	defer f.Close()
+
+	arch, ok := machoCPUArchitecture(f.Cpu)
+	if !ok {
+		return nil, fmt.Errorf("OpenMachOFatEntries: unsupported Mach-O architecture %v", f.Cpu)
+	}
+	return []Entry{{Arch: arch, Data: data}}, nil
+}
+
+// ParseModuleDataAllSlices runs ParseModuleData against every recognized
+// architecture slice of data (FAT or thin Mach-O), using each slice's own
+// Architecture for pointer size/endianness, and returns the results keyed
+// by architecture name. moduledataAt locates the raw moduledata bytes
+// within a single slice -- that's existing pclntab-scanning logic this
+// package doesn't otherwise contain, so callers supply it.
+func ParseModuleDataAllSlices(data []byte, version string, moduledataAt func(slice []byte) ([]byte, error)) (map[string]*ModuleData, error) {
+	entries, err := OpenMachOFatEntries(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*ModuleData, len(entries))
+	for _, e := range entries {
+		mdBytes, err := moduledataAt(e.Data)
+		if err != nil {
+			return nil, fmt.Errorf("ParseModuleDataAllSlices: %s: %v", e.Arch.Name, err)
+		}
+		md, err := ParseModuleDataArch(mdBytes, version, e.Arch)
+		if err != nil {
+			return nil, fmt.Errorf("ParseModuleDataAllSlices: %s: %v", e.Arch.Name, err)
+		}
+		results[e.Arch.Name] = md
+	}
+	return results, nil
+}
+
+// SelectModuleDataSlice is the "-arch" selector a CLI built on top of
+// ParseModuleDataAllSlices would need: archName picks one architecture's
+// ModuleData out of results by name. An empty archName returns a nil
+// ModuleData with a nil error, telling the caller to fall through to the
+// "emit JSON for every slice" default (EncodeModuleDataSlices) instead of
+// selecting one.
+func SelectModuleDataSlice(results map[string]*ModuleData, archName string) (*ModuleData, error) {
+	if archName == "" {
+		return nil, nil
+	}
+	md, ok := results[archName]
+	if !ok {
+		known := make([]string, 0, len(results))
+		for name := range results {
+			known = append(known, name)
+		}
+		return nil, fmt.Errorf("SelectModuleDataSlice: no slice for architecture %q (have: %v)", archName, known)
+	}
+	return md, nil
+}
+
+// EncodeModuleDataSlices is the "-arch" flag's default behavior when no
+// architecture is selected: JSON for every recognized slice, keyed by
+// architecture name, the same map ParseModuleDataAllSlices returns.
+func EncodeModuleDataSlices(results map[string]*ModuleData) ([]byte, error) {
+	out, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("EncodeModuleDataSlices: %v", err)
+	}
+	return out, nil
+}
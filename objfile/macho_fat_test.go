@@ -0,0 +1,134 @@
+package objfile
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+)
+
+// buildThinMachO returns a minimal valid Mach-O 64-bit header (no load
+// commands) for the given CPU type, just enough for debug/macho.NewFile to
+// parse it.
+func buildThinMachO(cpu macho.Cpu) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(macho.Magic64))
+	binary.Write(&buf, binary.LittleEndian, uint32(cpu))
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // subcpu
+	binary.Write(&buf, binary.LittleEndian, uint32(macho.TypeExec))
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // ncmd
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // cmdsz
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // flags
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // reserved
+	return buf.Bytes()
+}
+
+// buildFatMachO assembles a FAT container wrapping the given thin slices,
+// following the fat_header/fat_arch layout (always big-endian).
+func buildFatMachO(slices map[macho.Cpu][]byte) []byte {
+	var header bytes.Buffer
+	binary.Write(&header, binary.BigEndian, uint32(macho.MagicFat))
+	binary.Write(&header, binary.BigEndian, uint32(len(slices)))
+
+	const headerLen = 8
+	const archEntryLen = 20
+	offset := uint32(headerLen + archEntryLen*len(slices))
+
+	var archTable, payload bytes.Buffer
+	for cpu, slice := range slices {
+		binary.Write(&archTable, binary.BigEndian, uint32(cpu))
+		binary.Write(&archTable, binary.BigEndian, uint32(0)) // subcpu
+		binary.Write(&archTable, binary.BigEndian, offset)
+		binary.Write(&archTable, binary.BigEndian, uint32(len(slice)))
+		binary.Write(&archTable, binary.BigEndian, uint32(0)) // align
+		payload.Write(slice)
+		offset += uint32(len(slice))
+	}
+
+	var out bytes.Buffer
+	out.Write(header.Bytes())
+	out.Write(archTable.Bytes())
+	out.Write(payload.Bytes())
+	return out.Bytes()
+}
+
+func TestOpenMachOFatEntries(t *testing.T) {
+	amd64Slice := buildThinMachO(macho.CpuAmd64)
+	arm64Slice := buildThinMachO(macho.CpuArm64)
+	fat := buildFatMachO(map[macho.Cpu][]byte{
+		macho.CpuAmd64: amd64Slice,
+		macho.CpuArm64: arm64Slice,
+	})
+
+	entries, err := OpenMachOFatEntries(fat)
+	if err != nil {
+		t.Fatalf("OpenMachOFatEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	seen := map[string]bool{}
+	for _, e := range entries {
+		seen[e.Arch.Name] = true
+		if len(e.Data) == 0 {
+			t.Errorf("%s slice has no data", e.Arch.Name)
+		}
+	}
+	if !seen["amd64"] || !seen["arm64"] {
+		t.Errorf("got architectures %v, want amd64 and arm64", seen)
+	}
+}
+
+func TestOpenMachOFatEntriesThinFile(t *testing.T) {
+	entries, err := OpenMachOFatEntries(buildThinMachO(macho.CpuAmd64))
+	if err != nil {
+		t.Fatalf("OpenMachOFatEntries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Arch.Name != "amd64" {
+		t.Errorf("got %+v, want a single amd64 entry", entries)
+	}
+}
+
+func TestSelectModuleDataSlice(t *testing.T) {
+	results := map[string]*ModuleData{
+		"amd64": {TextVA: 0x1000},
+		"arm64": {TextVA: 0x2000},
+	}
+
+	md, err := SelectModuleDataSlice(results, "arm64")
+	if err != nil {
+		t.Fatalf("SelectModuleDataSlice failed: %v", err)
+	}
+	if md.TextVA != 0x2000 {
+		t.Errorf("got TextVA %#x, want %#x", md.TextVA, 0x2000)
+	}
+
+	if md, err := SelectModuleDataSlice(results, ""); err != nil || md != nil {
+		t.Errorf("SelectModuleDataSlice(\"\") = %v, %v, want nil, nil", md, err)
+	}
+
+	if _, err := SelectModuleDataSlice(results, "mips"); err == nil {
+		t.Error("SelectModuleDataSlice(unknown arch) succeeded, want error")
+	}
+}
+
+func TestEncodeModuleDataSlices(t *testing.T) {
+	results := map[string]*ModuleData{
+		"amd64": {TextVA: 0x1000},
+	}
+
+	out, err := EncodeModuleDataSlices(results)
+	if err != nil {
+		t.Fatalf("EncodeModuleDataSlices failed: %v", err)
+	}
+
+	var decoded map[string]*ModuleData
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("emitted JSON did not decode: %v", err)
+	}
+	if decoded["amd64"] == nil || decoded["amd64"].TextVA != 0x1000 {
+		t.Errorf("got %+v, want amd64 slice with TextVA 0x1000", decoded)
+	}
+}
@@ -0,0 +1,382 @@
+package objfile
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// goSlice is a zero-sized marker type used purely to tag a field as a Go
+// slice header (data/len/cap) in a bin-tagged layout struct. Its on-disk
+// size is driven entirely by the `slice` bin tag, not by reflect.Type.Size.
+type goSlice struct{}
+
+// binTag is the parsed form of a `bin:"..."` struct tag understood by
+// computeLayout. Components are comma-separated, e.g. `bin:"ptr,sinceGo:1.18"`.
+type binTag struct {
+	kind     string // "ptr", "slice", or "pad"
+	padWords int    // word count when kind == "pad"
+	sinceGo  string // field present only for versions >= sinceGo (inclusive)
+	untilGo  string // field present only for versions <= untilGo (inclusive)
+}
+
+func parseBinTag(tag string) (binTag, error) {
+	var bt binTag
+	if tag == "" {
+		return bt, fmt.Errorf("missing bin tag")
+	}
+	for _, part := range strings.Split(tag, ",") {
+		switch {
+		case part == "ptr":
+			bt.kind = "ptr"
+		case part == "slice":
+			bt.kind = "slice"
+		case strings.HasPrefix(part, "pad:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(part, "pad:"))
+			if err != nil {
+				return bt, fmt.Errorf("invalid pad count %q: %v", part, err)
+			}
+			bt.kind = "pad"
+			bt.padWords = n
+		case strings.HasPrefix(part, "sinceGo:"):
+			bt.sinceGo = strings.TrimPrefix(part, "sinceGo:")
+		case strings.HasPrefix(part, "untilGo:"):
+			bt.untilGo = strings.TrimPrefix(part, "untilGo:")
+		default:
+			return bt, fmt.Errorf("unknown bin tag component %q", part)
+		}
+	}
+	if bt.kind == "" {
+		return bt, fmt.Errorf("bin tag %q does not specify a field kind (ptr/slice/pad)", tag)
+	}
+	return bt, nil
+}
+
+// compareGoVersion compares two dotted "major.minor" Go version strings
+// numerically (unlike strings.Compare, "1.18" sorts after "1.7"). Versions
+// that fail to parse compare as equal to avoid spurious panics; callers
+// only ever pass the well-known "1.N" strings used by versionLayoutMap.
+func compareGoVersion(a, b string) int {
+	av, aok := parseGoVersion(a)
+	bv, bok := parseGoVersion(b)
+	if !aok || !bok {
+		return strings.Compare(a, b)
+	}
+	if av[0] != bv[0] {
+		return av[0] - bv[0]
+	}
+	return av[1] - bv[1]
+}
+
+func parseGoVersion(v string) ([2]int, bool) {
+	parts := strings.SplitN(v, ".", 2)
+	if len(parts) != 2 {
+		return [2]int{}, false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return [2]int{}, false
+	}
+	return [2]int{major, minor}, true
+}
+
+// computeLayout walks structType field-by-field in declaration order and
+// derives the FieldOffset of every bin-tagged field for the given pointer
+// size and Go version. Fields gated by sinceGo/untilGo that don't apply to
+// version are reported as the zero FieldOffset, matching how the old
+// hand-maintained versionLayoutMap marked a field "not present".
+func computeLayout(structType reflect.Type, ptrSize uint64, version string) (ModuleDataLayout, error) {
+	if structType.Kind() != reflect.Struct {
+		return ModuleDataLayout{}, fmt.Errorf("computeLayout: %s is not a struct", structType)
+	}
+
+	layout := ModuleDataLayout{PtrSize: ptrSize}
+	var offset uint64
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		bt, err := parseBinTag(field.Tag.Get("bin"))
+		if err != nil {
+			return ModuleDataLayout{}, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+
+		var size uint64
+		switch bt.kind {
+		case "ptr":
+			size = ptrSize
+		case "slice":
+			size = 3 * ptrSize
+		case "pad":
+			size = uint64(bt.padWords) * ptrSize
+		}
+
+		present := true
+		if bt.sinceGo != "" && compareGoVersion(version, bt.sinceGo) < 0 {
+			present = false
+		}
+		if bt.untilGo != "" && compareGoVersion(version, bt.untilGo) > 0 {
+			present = false
+		}
+
+		if bt.kind != "pad" {
+			if present {
+				setLayoutField(&layout, field.Name, FieldOffset{Offset: offset, Size: size})
+			} else {
+				setLayoutField(&layout, field.Name, FieldOffset{})
+			}
+		}
+
+		if present {
+			offset += size
+		}
+	}
+
+	// Go 1.5/1.6's moduledata predates a standalone typelinks slice: the
+	// type link table lived where LegacyTypes is now. legacyModuleDataFields
+	// only tags LegacyTypes, so alias Typelinks onto the same bytes here
+	// instead of tagging both fields and double-counting the slice's size.
+	// This matches the old hand-maintained versionLayoutMap, which pointed
+	// both fields at the same offset for "1.5"/"1.5_64".
+	if _, hasLegacyTypes := structType.FieldByName("LegacyTypes"); hasLegacyTypes {
+		if _, hasTypelinks := structType.FieldByName("Typelinks"); !hasTypelinks {
+			layout.Typelinks = layout.LegacyTypes
+		}
+	}
+
+	return layout, nil
+}
+
+// unmarshalModuleData walks structType field-by-field exactly like
+// computeLayout, but instead of recording each present field's FieldOffset,
+// reads its bytes straight out of data and assigns them into a fresh
+// ModuleData. This is the "unmarshal directly into the bin-tagged structs"
+// path ParseModuleData uses in place of a hand-written readField/readSlice
+// call per field per Go version band.
+func unmarshalModuleData(data []byte, structType reflect.Type, ptrSize uint64, version string, littleEndian bool) (*ModuleData, error) {
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unmarshalModuleData: %s is not a struct", structType)
+	}
+
+	md := &ModuleData{}
+	var offset uint64
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		bt, err := parseBinTag(field.Tag.Get("bin"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+
+		var size uint64
+		switch bt.kind {
+		case "ptr":
+			size = ptrSize
+		case "slice":
+			size = 3 * ptrSize
+		case "pad":
+			size = uint64(bt.padWords) * ptrSize
+		}
+
+		present := true
+		if bt.sinceGo != "" && compareGoVersion(version, bt.sinceGo) < 0 {
+			present = false
+		}
+		if bt.untilGo != "" && compareGoVersion(version, bt.untilGo) > 0 {
+			present = false
+		}
+
+		if present {
+			fo := FieldOffset{Offset: offset, Size: size}
+			switch bt.kind {
+			case "ptr":
+				v, err := readField(data, fo, littleEndian)
+				if err != nil {
+					return nil, fmt.Errorf("unmarshalModuleData: field %s: %v", field.Name, err)
+				}
+				if err := setModuleDataPtrField(md, field.Name, v); err != nil {
+					return nil, err
+				}
+			case "slice":
+				v, err := readSlice(data, fo, ptrSize, littleEndian)
+				if err != nil {
+					return nil, fmt.Errorf("unmarshalModuleData: field %s: %v", field.Name, err)
+				}
+				if err := setModuleDataSliceField(md, field.Name, v); err != nil {
+					return nil, err
+				}
+			}
+			offset += size
+		}
+	}
+
+	// Go 1.5/1.6's moduledata predates a standalone typelinks slice (see
+	// the matching note on computeLayout): alias Typelinks onto the bytes
+	// LegacyTypes was just unmarshaled from instead of reading them twice.
+	if _, hasLegacyTypes := structType.FieldByName("LegacyTypes"); hasLegacyTypes {
+		if _, hasTypelinks := structType.FieldByName("Typelinks"); !hasTypelinks {
+			md.Typelinks = md.LegacyTypes
+		}
+	}
+
+	return md, nil
+}
+
+// setModuleDataPtrField assigns a decoded pointer-shaped value into the
+// matching named field of ModuleData. Layout structs must use these exact
+// field names (the same ones setLayoutField recognizes) for a `bin:"ptr"`
+// field to reach ModuleData at all.
+func setModuleDataPtrField(md *ModuleData, name string, v uint64) error {
+	switch name {
+	case "Text":
+		md.TextVA = v
+	case "Minpc":
+		md.Minpc = v
+	case "Types":
+		md.Types = v
+	case "ETypes":
+		md.ETypes = v
+	case "Rodata":
+		md.Rodata = v
+	case "Gofunc":
+		md.Gofunc = v
+	case "Covctrs":
+		md.Covctrs = v
+	case "Ecovctrs":
+		md.Ecovctrs = v
+	default:
+		return fmt.Errorf("unmarshalModuleData: unknown ptr field %q", name)
+	}
+	return nil
+}
+
+// setModuleDataSliceField assigns a decoded GoSlice64 into the matching
+// named field of ModuleData. Textsectmap has no ModuleData counterpart --
+// the original hand-written ParseModuleData never surfaced it either -- so
+// it's tagged and walked for its byte offset like any other field but
+// intentionally dropped here rather than erroring.
+func setModuleDataSliceField(md *ModuleData, name string, v GoSlice64) error {
+	switch name {
+	case "Typelinks":
+		md.Typelinks = v
+	case "ITablinks":
+		md.ITablinks = v
+	case "LegacyTypes":
+		md.LegacyTypes = v
+	case "InitTasks":
+		md.InitTasks = v
+	case "Ftab":
+		md.Ftab = v
+	case "Textsectmap":
+		return nil
+	default:
+		return fmt.Errorf("unmarshalModuleData: unknown slice field %q", name)
+	}
+	return nil
+}
+
+// setLayoutField assigns a computed FieldOffset into the matching named
+// field of ModuleDataLayout. Layout structs registered via RegisterLayout
+// must use these exact field names for any field they want surfaced.
+func setLayoutField(layout *ModuleDataLayout, name string, fo FieldOffset) {
+	switch name {
+	case "Text":
+		layout.Text = fo
+	case "Types":
+		layout.Types = fo
+	case "ETypes":
+		layout.ETypes = fo
+	case "Typelinks":
+		layout.Typelinks = fo
+	case "ITablinks":
+		layout.ITablinks = fo
+	case "Ftab":
+		layout.Ftab = fo
+	case "Minpc":
+		layout.Minpc = fo
+	case "Textsectmap":
+		layout.Textsectmap = fo
+	case "LegacyTypes":
+		layout.LegacyTypes = fo
+	case "Rodata":
+		layout.Rodata = fo
+	case "Gofunc":
+		layout.Gofunc = fo
+	case "Covctrs":
+		layout.Covctrs = fo
+	case "Ecovctrs":
+		layout.Ecovctrs = fo
+	case "InitTasks":
+		layout.InitTasks = fo
+	}
+}
+
+// legacyModuleDataFields mirrors the Go 1.5/1.6 moduledata layout, which
+// predates the types/etypes/itablinks fields entirely.
+type legacyModuleDataFields struct {
+	_padLead    [2]uintptr `bin:"pad:2"`
+	Ftab        goSlice    `bin:"slice"`
+	_pad0       [3]uintptr `bin:"pad:3"`
+	Minpc       uintptr    `bin:"ptr"`
+	_pad1       [7]uintptr `bin:"pad:7"`
+	Text        uintptr    `bin:"ptr"`
+	_pad2       [7]uintptr `bin:"pad:7"`
+	LegacyTypes goSlice    `bin:"slice"`
+}
+
+// moduleDataFields mirrors the Go 1.7+ moduledata layout. Fields added in
+// later releases carry a sinceGo tag so the same struct definition serves
+// every 1.7-and-up version band; computeLayout zeroes out any field that
+// doesn't apply to the requested version.
+type moduleDataFields struct {
+	_padLead    [2]uintptr `bin:"pad:2"`
+	Ftab        goSlice    `bin:"slice"`
+	_pad0       [3]uintptr `bin:"pad:3"`
+	Minpc       uintptr    `bin:"ptr"`
+	_pad1       [7]uintptr `bin:"pad:7"`
+	Text        uintptr    `bin:"ptr"`
+	_pad2       [7]uintptr `bin:"pad:7"`
+	Types       uintptr    `bin:"ptr"`
+	ETypes      uintptr    `bin:"ptr"`
+	Typelinks   goSlice    `bin:"slice"`
+	ITablinks   goSlice    `bin:"slice"`
+	Rodata      uintptr    `bin:"ptr,sinceGo:1.18"`
+	Gofunc      uintptr    `bin:"ptr,sinceGo:1.18"`
+	Textsectmap goSlice    `bin:"slice,sinceGo:1.18"`
+	Covctrs     uintptr    `bin:"ptr,sinceGo:1.20"`
+	Ecovctrs    uintptr    `bin:"ptr,sinceGo:1.20"`
+	InitTasks   goSlice    `bin:"slice,sinceGo:1.22"`
+}
+
+// registeredLayouts holds layout structs plugged in via RegisterLayout,
+// keyed the same way as the builtin bands ("<version>" / "<version>_64").
+var registeredLayouts = map[string]reflect.Type{}
+
+// RegisterLayout lets callers plug in a layout for a Go version that
+// GoReSym doesn't ship a builtin struct for yet (e.g. an unreleased or
+// experimental moduledata shape). v must be a struct value whose fields
+// are annotated with `bin` tags understood by computeLayout; its field
+// names should match the ones ModuleDataLayout exposes (Text, Types,
+// Typelinks, ...). The registered layout takes precedence over the
+// builtin bands for that exact version key.
+func RegisterLayout(version string, v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return fmt.Errorf("RegisterLayout(%s): nil layout value", version)
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("RegisterLayout(%s): layout must be a struct, got %s", version, t.Kind())
+	}
+	registeredLayouts[version] = t
+	return nil
+}
+
+// builtinLayoutFor returns the bin-tagged struct type that describes the
+// given Go version's moduledata shape, independent of pointer size.
+func builtinLayoutFor(version string) reflect.Type {
+	if strings.HasPrefix(version, "1.5") || strings.HasPrefix(version, "1.6") {
+		return reflect.TypeOf(legacyModuleDataFields{})
+	}
+	return reflect.TypeOf(moduleDataFields{})
+}
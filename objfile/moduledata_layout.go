@@ -35,170 +35,62 @@ type ModuleDataLayout struct {
 	PtrSize uint64
 }
 
-// versionLayoutMap maps Go versions to their corresponding moduledata layouts
-var versionLayoutMap = map[string]ModuleDataLayout{
-	// Go 1.5-1.6 layout (32-bit)
-	"1.5": {
-		Text:       FieldOffset{Offset: 0x40, Size: 4},
-		Types:      FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		ETypes:     FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		Typelinks:  FieldOffset{Offset: 0x60, Size: 12},
-		ITablinks:  FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		Ftab:       FieldOffset{Offset: 0x8, Size: 12},
-		Minpc:      FieldOffset{Offset: 0x20, Size: 4},
-		Textsectmap: FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		LegacyTypes: FieldOffset{Offset: 0x60, Size: 12},
-		PtrSize:    4,
-	},
-
-	// Go 1.5-1.6 layout (64-bit)
-	"1.5_64": {
-		Text:       FieldOffset{Offset: 0x80, Size: 8},
-		Types:      FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		ETypes:     FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		Typelinks:  FieldOffset{Offset: 0xc0, Size: 24},
-		ITablinks:  FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		Ftab:       FieldOffset{Offset: 0x10, Size: 24},
-		Minpc:      FieldOffset{Offset: 0x40, Size: 8},
-		Textsectmap: FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.5
-		LegacyTypes: FieldOffset{Offset: 0xc0, Size: 24},
-		PtrSize:    8,
-	},
-
-	// Go 1.7-1.17 layout (32-bit)
-	"1.7": {
-		Text:       FieldOffset{Offset: 0x40, Size: 4},
-		Types:      FieldOffset{Offset: 0x60, Size: 4},
-		ETypes:     FieldOffset{Offset: 0x64, Size: 4},
-		Typelinks:  FieldOffset{Offset: 0x68, Size: 12},
-		ITablinks:  FieldOffset{Offset: 0x74, Size: 12},
-		Ftab:       FieldOffset{Offset: 0x8, Size: 12},
-		Minpc:      FieldOffset{Offset: 0x20, Size: 4},
-		Textsectmap: FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.7
-		PtrSize:    4,
-	},
-
-	// Go 1.7-1.17 layout (64-bit)
-	"1.7_64": {
-		Text:       FieldOffset{Offset: 0x80, Size: 8},
-		Types:      FieldOffset{Offset: 0xc0, Size: 8},
-		ETypes:     FieldOffset{Offset: 0xc8, Size: 8},
-		Typelinks:  FieldOffset{Offset: 0xd0, Size: 24},
-		ITablinks:  FieldOffset{Offset: 0xe8, Size: 24},
-		Ftab:       FieldOffset{Offset: 0x10, Size: 24},
-		Minpc:      FieldOffset{Offset: 0x40, Size: 8},
-		Textsectmap: FieldOffset{Offset: 0x0, Size: 0}, // Not present in 1.7
-		PtrSize:    8,
-	},
-
-	// Go 1.18-1.19 layout (32-bit)
-	"1.18": {
-		Text:       FieldOffset{Offset: 0x40, Size: 4},
-		Types:      FieldOffset{Offset: 0x60, Size: 4},
-		ETypes:     FieldOffset{Offset: 0x64, Size: 4},
-		Typelinks:  FieldOffset{Offset: 0x68, Size: 12},
-		ITablinks:  FieldOffset{Offset: 0x74, Size: 12},
-		Ftab:       FieldOffset{Offset: 0x8, Size: 12},
-		Minpc:      FieldOffset{Offset: 0x20, Size: 4},
-		Textsectmap: FieldOffset{Offset: 0x80, Size: 12},
-		Rodata:     FieldOffset{Offset: 0x70, Size: 4},
-		Gofunc:     FieldOffset{Offset: 0x74, Size: 4},
-		PtrSize:    4,
-	},
-
-	// Go 1.18-1.19 layout (64-bit)
-	"1.18_64": {
-		Text:       FieldOffset{Offset: 0x80, Size: 8},
-		Types:      FieldOffset{Offset: 0xc0, Size: 8},
-		ETypes:     FieldOffset{Offset: 0xc8, Size: 8},
-		Typelinks:  FieldOffset{Offset: 0xd0, Size: 24},
-		ITablinks:  FieldOffset{Offset: 0xe8, Size: 24},
-		Ftab:       FieldOffset{Offset: 0x10, Size: 24},
-		Minpc:      FieldOffset{Offset: 0x40, Size: 8},
-		Textsectmap: FieldOffset{Offset: 0x100, Size: 24},
-		Rodata:     FieldOffset{Offset: 0xe0, Size: 8},
-		Gofunc:     FieldOffset{Offset: 0xe8, Size: 8},
-		PtrSize:    8,
-	},
-
-	// Go 1.20-1.21 layout (32-bit)
-	"1.20": {
-		Text:       FieldOffset{Offset: 0x40, Size: 4},
-		Types:      FieldOffset{Offset: 0x60, Size: 4},
-		ETypes:     FieldOffset{Offset: 0x64, Size: 4},
-		Typelinks:  FieldOffset{Offset: 0x68, Size: 12},
-		ITablinks:  FieldOffset{Offset: 0x74, Size: 12},
-		Ftab:       FieldOffset{Offset: 0x8, Size: 12},
-		Minpc:      FieldOffset{Offset: 0x20, Size: 4},
-		Textsectmap: FieldOffset{Offset: 0x80, Size: 12},
-		Rodata:     FieldOffset{Offset: 0x70, Size: 4},
-		Gofunc:     FieldOffset{Offset: 0x74, Size: 4},
-		Covctrs:    FieldOffset{Offset: 0x78, Size: 4},
-		Ecovctrs:   FieldOffset{Offset: 0x7c, Size: 4},
-		PtrSize:    4,
-	},
-
-	// Go 1.20-1.21 layout (64-bit)
-	"1.20_64": {
-		Text:       FieldOffset{Offset: 0x80, Size: 8},
-		Types:      FieldOffset{Offset: 0xc0, Size: 8},
-		ETypes:     FieldOffset{Offset: 0xc8, Size: 8},
-		Typelinks:  FieldOffset{Offset: 0xd0, Size: 24},
-		ITablinks:  FieldOffset{Offset: 0xe8, Size: 24},
-		Ftab:       FieldOffset{Offset: 0x10, Size: 24},
-		Minpc:      FieldOffset{Offset: 0x40, Size: 8},
-		Textsectmap: FieldOffset{Offset: 0x100, Size: 24},
-		Rodata:     FieldOffset{Offset: 0xe0, Size: 8},
-		Gofunc:     FieldOffset{Offset: 0xe8, Size: 8},
-		Covctrs:    FieldOffset{Offset: 0xf0, Size: 8},
-		Ecovctrs:   FieldOffset{Offset: 0xf8, Size: 8},
-		PtrSize:    8,
-	},
-
-	// Go 1.22 layout (32-bit)
-	"1.22": {
-		Text:       FieldOffset{Offset: 0x40, Size: 4},
-		Types:      FieldOffset{Offset: 0x60, Size: 4},
-		ETypes:     FieldOffset{Offset: 0x64, Size: 4},
-		Typelinks:  FieldOffset{Offset: 0x68, Size: 12},
-		ITablinks:  FieldOffset{Offset: 0x74, Size: 12},
-		Ftab:       FieldOffset{Offset: 0x8, Size: 12},
-		Minpc:      FieldOffset{Offset: 0x20, Size: 4},
-		Textsectmap: FieldOffset{Offset: 0x80, Size: 12},
-		Rodata:     FieldOffset{Offset: 0x70, Size: 4},
-		Gofunc:     FieldOffset{Offset: 0x74, Size: 4},
-		Covctrs:    FieldOffset{Offset: 0x78, Size: 4},
-		Ecovctrs:   FieldOffset{Offset: 0x7c, Size: 4},
-		InitTasks:  FieldOffset{Offset: 0x8c, Size: 12},
-		PtrSize:    4,
-	},
-
-	// Go 1.22 layout (64-bit)
-	"1.22_64": {
-		Text:       FieldOffset{Offset: 0x80, Size: 8},
-		Types:      FieldOffset{Offset: 0xc0, Size: 8},
-		ETypes:     FieldOffset{Offset: 0xc8, Size: 8},
-		Typelinks:  FieldOffset{Offset: 0xd0, Size: 24},
-		ITablinks:  FieldOffset{Offset: 0xe8, Size: 24},
-		Ftab:       FieldOffset{Offset: 0x10, Size: 24},
-		Minpc:      FieldOffset{Offset: 0x40, Size: 8},
-		Textsectmap: FieldOffset{Offset: 0x100, Size: 24},
-		Rodata:     FieldOffset{Offset: 0xe0, Size: 8},
-		Gofunc:     FieldOffset{Offset: 0xe8, Size: 8},
-		Covctrs:    FieldOffset{Offset: 0xf0, Size: 8},
-		Ecovctrs:   FieldOffset{Offset: 0xf8, Size: 8},
-		InitTasks:  FieldOffset{Offset: 0x118, Size: 24},
-		PtrSize:    8,
-	},
+// knownVersionBands lists every Go version band GoReSym ships a builtin
+// bin-tagged layout struct for. versionLayoutCache is populated from this
+// list at init time instead of hand-transcribing offsets per version: see
+// computeLayout and RegisterLayout in moduledata_binlayout.go.
+var knownVersionBands = []string{"1.5", "1.7", "1.18", "1.20", "1.22"}
+
+// versionLayoutCache holds the ModuleDataLayout computed for every known
+// version band, keyed the same way the old hand-maintained table was
+// ("<version>" for 32-bit, "<version>_64" for 64-bit).
+var versionLayoutCache = buildVersionLayoutCache()
+
+func buildVersionLayoutCache() map[string]ModuleDataLayout {
+	cache := make(map[string]ModuleDataLayout, 2*len(knownVersionBands))
+	for _, version := range knownVersionBands {
+		for _, is64bit := range []bool{false, true} {
+			ptrSize := uint64(4)
+			if is64bit {
+				ptrSize = 8
+			}
+			layout, err := computeLayout(builtinLayoutFor(version), ptrSize, version)
+			if err != nil {
+				// A builtin layout struct failing to compute is a
+				// programming error (bad bin tag), not a runtime
+				// condition callers can recover from.
+				panic(fmt.Sprintf("objfile: builtin layout %s (64bit=%v): %v", version, is64bit, err))
+			}
+			key := version
+			if is64bit {
+				key += "_64"
+			}
+			cache[key] = layout
+		}
+	}
+	return cache
 }
 
-// getLayout returns the appropriate ModuleDataLayout for the given version and architecture
+// getLayout returns the appropriate ModuleDataLayout for the given version and architecture.
+// A layout registered via RegisterLayout for this exact version takes precedence over the
+// builtin bands computed in buildVersionLayoutCache.
 func getLayout(version string, is64bit bool) ModuleDataLayout {
 	key := version
 	if is64bit {
 		key += "_64"
 	}
-	if layout, ok := versionLayoutMap[key]; ok {
+
+	ptrSize := uint64(4)
+	if is64bit {
+		ptrSize = 8
+	}
+	if structType, ok := registeredLayouts[version]; ok {
+		if layout, err := computeLayout(structType, ptrSize, version); err == nil {
+			return layout
+		}
+	}
+
+	if layout, ok := versionLayoutCache[key]; ok {
 		return layout
 	}
 	// Return a default layout if version not found
@@ -0,0 +1,212 @@
+package objfile
+
+import (
+	"fmt"
+)
+
+// ParseModuleData parses a moduledata blob by unmarshaling directly into the
+// bin-tagged layout struct for version (see moduleDataFields/
+// legacyModuleDataFields and unmarshalModuleData in moduledata_binlayout.go),
+// instead of hand-reading one readField/readSlice call per field per version
+// band. A version registered via RegisterLayout takes precedence over the
+// builtin bands, same as getLayout.
+func ParseModuleData(data []byte, version string, is64bit bool, littleEndian bool) (*ModuleData, error) {
+	ptrSize := uint64(4)
+	if is64bit {
+		ptrSize = 8
+	}
+
+	structType, ok := registeredLayouts[version]
+	if !ok {
+		structType = builtinLayoutFor(version)
+	}
+
+	return unmarshalModuleData(data, structType, ptrSize, version, littleEndian)
+}
+
+// ParseModuleDataArch is ParseModuleData parametrized by Architecture instead
+// of separate is64bit/littleEndian flags, so callers scanning a big-endian
+// binary (ppc64, s390x, mips) don't have to remember which bool means which.
+func ParseModuleDataArch(data []byte, version string, arch Architecture) (*ModuleData, error) {
+	return ParseModuleData(data, version, arch.is64bit(), arch.littleEndian())
+}
+
+// maxPlausibleSliceFraction bounds how large a moduledata slice's Len/Capacity
+// is allowed to be relative to the scanned file's size. A genuine Typelinks or
+// ITablinks slice never approaches the size of the binary it lives in; seeing
+// one that does is a strong signal the endianness (or layout) guess was wrong
+// and the "length" field is actually a byte-swapped garbage value.
+const maxPlausibleSliceFraction = 4
+
+// ValidateModuleData performs validation checks on the parsed moduledata.
+// fileSize is the size in bytes of the section the moduledata was read from;
+// it is used to reject implausibly large slice lengths that typically
+// indicate the endianness was misdetected rather than a genuinely huge slice.
+func ValidateModuleData(md *ModuleData, firstFuncEntry uint64, fileSize uint64) error {
+	// Check if the first function's entry matches the minpc value
+	if md.TextVA != firstFuncEntry {
+		return fmt.Errorf("TextVA value %x does not match first function entry %x", md.TextVA, firstFuncEntry)
+	}
+
+	maxPlausibleLen := fileSize / maxPlausibleSliceFraction
+
+	// Validate slice fields
+	if md.Typelinks.Len > md.Typelinks.Capacity {
+		return fmt.Errorf("invalid Typelinks slice: len %d > capacity %d", md.Typelinks.Len, md.Typelinks.Capacity)
+	}
+	if err := checkPlausibleSliceLen("Typelinks", md.Typelinks.Len, maxPlausibleLen); err != nil {
+		return err
+	}
+
+	if md.ITablinks.Len > md.ITablinks.Capacity {
+		return fmt.Errorf("invalid ITablinks slice: len %d > capacity %d", md.ITablinks.Len, md.ITablinks.Capacity)
+	}
+	if err := checkPlausibleSliceLen("ITablinks", md.ITablinks.Len, maxPlausibleLen); err != nil {
+		return err
+	}
+
+	if md.LegacyTypes.Len > md.LegacyTypes.Capacity {
+		return fmt.Errorf("invalid LegacyTypes slice: len %d > capacity %d", md.LegacyTypes.Len, md.LegacyTypes.Capacity)
+	}
+	if err := checkPlausibleSliceLen("LegacyTypes", md.LegacyTypes.Len, maxPlausibleLen); err != nil {
+		return err
+	}
+
+	if md.InitTasks.Len > md.InitTasks.Capacity {
+		return fmt.Errorf("invalid InitTasks slice: len %d > capacity %d", md.InitTasks.Len, md.InitTasks.Capacity)
+	}
+	if err := checkPlausibleSliceLen("InitTasks", md.InitTasks.Len, maxPlausibleLen); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkPlausibleSliceLen rejects a slice length that exceeds maxPlausibleLen,
+// which is treated as unbounded when fileSize (and therefore maxPlausibleLen)
+// is zero since no file-size context was provided.
+func checkPlausibleSliceLen(field string, length, maxPlausibleLen uint64) error {
+	if maxPlausibleLen == 0 {
+		return nil
+	}
+	if length > maxPlausibleLen {
+		return fmt.Errorf("implausible %s length %d exceeds %d (likely endianness misdetection)", field, length, maxPlausibleLen)
+	}
+	return nil
+}
+
+// maxPlausibleVA bounds how large a pointer-shaped moduledata field is
+// allowed to look before DetectLayout treats it as garbage rather than a
+// real virtual address. A genuine Types/Typelinks.Data pointer from a Go
+// binary never approaches this; a wrong layout guess tends to produce
+// either zero or a wildly out-of-range value instead.
+const maxPlausibleVA = 1 << 40
+
+// maxPlausibleMinpcDelta bounds how far Minpc is allowed to sit from TextVA;
+// the two describe the same text section and are normally within a few
+// bytes of each other.
+const maxPlausibleMinpcDelta = 0x1000
+
+// DetectLayout recovers the Go version, pointer width, and byte order of a
+// moduledata blob whose buildinfo stamp has been stripped or wiped -- a
+// common anti-analysis trick against Go malware that otherwise forces a
+// caller to already know which version/bitness/endianness to hand
+// ParseModuleData. It tries every layout band GoReSym knows (including any
+// plugged in via RegisterLayout) against data, in both byte orders --
+// skipping the big-endian candidates would leave DetectLayout unable to
+// recover ppc64/s390x/mips binaries at all -- scores how plausible each
+// resulting parse looks relative to firstFuncEntry, and returns the
+// highest-scoring candidate. Ties are broken toward the newest Go version,
+// then toward little-endian, since both are the more likely match for
+// binaries encountered today.
+func DetectLayout(data []byte, firstFuncEntry uint64) (version string, is64bit bool, littleEndian bool, err error) {
+	bestScore := -1
+	found := false
+
+	candidateVersions := make([]string, len(knownVersionBands), len(knownVersionBands)+len(registeredLayouts))
+	copy(candidateVersions, knownVersionBands)
+	for registeredVersion := range registeredLayouts {
+		candidateVersions = append(candidateVersions, registeredVersion)
+	}
+
+	for _, candidateVersion := range candidateVersions {
+		for _, candidate64bit := range []bool{true, false} {
+			for _, candidateLittleEndian := range []bool{true, false} {
+				md, parseErr := ParseModuleData(data, candidateVersion, candidate64bit, candidateLittleEndian)
+				if parseErr != nil {
+					continue
+				}
+				score := scoreModuleDataCandidate(md, firstFuncEntry)
+				if score < 0 {
+					continue
+				}
+				if !found || score > bestScore ||
+					(score == bestScore && compareGoVersion(candidateVersion, version) > 0) ||
+					(score == bestScore && candidateVersion == version && candidateLittleEndian && !littleEndian) {
+					version, is64bit, littleEndian, bestScore, found = candidateVersion, candidate64bit, candidateLittleEndian, score, true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return "", false, false, fmt.Errorf("DetectLayout: no known layout plausibly matches firstFuncEntry %#x", firstFuncEntry)
+	}
+	return version, is64bit, littleEndian, nil
+}
+
+// scoreModuleDataCandidate rates how plausible md looks as a real parse of
+// the scanned binary's moduledata. It returns -1 if md violates an
+// invariant that can never hold for a genuine moduledata (disqualifying the
+// candidate outright), otherwise a non-negative score where higher is more
+// plausible.
+func scoreModuleDataCandidate(md *ModuleData, firstFuncEntry uint64) int {
+	if md.TextVA != firstFuncEntry {
+		return -1
+	}
+	score := 1
+
+	if md.Typelinks.Len > md.Typelinks.Capacity {
+		return -1
+	}
+	if md.ITablinks.Len > md.ITablinks.Capacity {
+		return -1
+	}
+
+	if md.Types != 0 || md.ETypes != 0 {
+		if md.Types > md.ETypes {
+			return -1
+		}
+		score++
+	}
+
+	if md.Covctrs != 0 || md.Ecovctrs != 0 {
+		if md.Covctrs > md.Ecovctrs {
+			return -1
+		}
+		score++
+	}
+
+	if md.Minpc != 0 {
+		delta := md.Minpc - md.TextVA
+		if md.Minpc < md.TextVA {
+			delta = md.TextVA - md.Minpc
+		}
+		if delta > maxPlausibleMinpcDelta {
+			return -1
+		}
+		score++
+	}
+
+	for _, ptr := range []uint64{md.Types, md.ETypes, uint64(md.Typelinks.Data), uint64(md.ITablinks.Data)} {
+		if ptr == 0 {
+			continue
+		}
+		if ptr > maxPlausibleVA {
+			return -1
+		}
+		score++
+	}
+
+	return score
+} 
\ No newline at end of file
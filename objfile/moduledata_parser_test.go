@@ -0,0 +1,337 @@
+package objfile
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// createTestModuleData creates a byte slice containing test moduledata for a
+// specific version, architecture, and byte order.
+func createTestModuleData(version string, is64bit bool, littleEndian bool) []byte {
+	// Create a buffer large enough to hold the largest moduledata structure
+	buf := make([]byte, 1024)
+
+	// Get the layout for this version
+	layout := getLayout(version, is64bit)
+
+	order := func() binary.ByteOrder {
+		if littleEndian {
+			return binary.LittleEndian
+		}
+		return binary.BigEndian
+	}()
+
+	// Helper function to write a value at an offset
+	writeAt := func(offset FieldOffset, value uint64) {
+		if offset.Size == 0 {
+			return // Skip writing if field doesn't exist
+		}
+		if is64bit {
+			order.PutUint64(buf[offset.Offset:], value)
+		} else {
+			order.PutUint32(buf[offset.Offset:], uint32(value))
+		}
+	}
+
+	// Helper function to write a slice at an offset
+	writeSlice := func(offset FieldOffset, data, length, capacity uint64) {
+		if offset.Size == 0 {
+			return // Skip writing if field doesn't exist
+		}
+		if is64bit {
+			order.PutUint64(buf[offset.Offset:], data)
+			order.PutUint64(buf[offset.Offset+8:], length)
+			order.PutUint64(buf[offset.Offset+16:], capacity)
+		} else {
+			order.PutUint32(buf[offset.Offset:], uint32(data))
+			order.PutUint32(buf[offset.Offset+4:], uint32(length))
+			order.PutUint32(buf[offset.Offset+8:], uint32(capacity))
+		}
+	}
+	
+	// Write common fields
+	writeAt(layout.Text, 0x1000)
+	writeAt(layout.Types, 0x2000)
+	writeAt(layout.ETypes, 0x3000)
+	writeAt(layout.Minpc, 0x4000)
+	
+	// Write slice fields
+	writeSlice(layout.Typelinks, 0x5000, 10, 20)
+	writeSlice(layout.ITablinks, 0x6000, 15, 25)
+	writeSlice(layout.Ftab, 0x7000, 20, 30)
+	
+	// Write version-specific fields
+	if version == "1.5" {
+		writeSlice(layout.LegacyTypes, 0x8000, 10, 20)
+	} else if version >= "1.18" {
+		writeAt(layout.Rodata, 0x9000)
+		writeAt(layout.Gofunc, 0xa000)
+		if version >= "1.20" {
+			writeAt(layout.Covctrs, 0xb000)
+			writeAt(layout.Ecovctrs, 0xc000)
+			if version >= "1.22" {
+				writeSlice(layout.InitTasks, 0xd000, 5, 10)
+			}
+		}
+	}
+	
+	return buf
+}
+
+func TestModuleDataParsing(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		is64bit  bool
+		expected *ModuleData
+	}{
+		{
+			// Go 1.5 moduledata predates Types/ETypes/ITablinks entirely (they
+			// read back as the zero GoSlice64/0, same as the old hand-maintained
+			// table): only Ftab/Minpc/Text and the single type-link slice exist.
+			// That slice is exposed as both Typelinks and LegacyTypes -- 1.5 has
+			// no separate "legacy" field, so the two alias the same offset, and
+			// the later-executed 1.5-specific write is what ends up on disk.
+			name:    "Go 1.5 32-bit",
+			version: "1.5",
+			is64bit: false,
+			expected: &ModuleData{
+				TextVA:      0x1000,
+				Minpc:       0x4000,
+				Typelinks:   GoSlice64{Data: 0x8000, Len: 10, Capacity: 20},
+				LegacyTypes: GoSlice64{Data: 0x8000, Len: 10, Capacity: 20},
+				Ftab:        GoSlice64{Data: 0x7000, Len: 20, Capacity: 30},
+			},
+		},
+		{
+			name:    "Go 1.5 64-bit",
+			version: "1.5",
+			is64bit: true,
+			expected: &ModuleData{
+				TextVA:      0x1000,
+				Minpc:       0x4000,
+				Typelinks:   GoSlice64{Data: 0x8000, Len: 10, Capacity: 20},
+				LegacyTypes: GoSlice64{Data: 0x8000, Len: 10, Capacity: 20},
+				Ftab:        GoSlice64{Data: 0x7000, Len: 20, Capacity: 30},
+			},
+		},
+		{
+			name:    "Go 1.18 32-bit",
+			version: "1.18",
+			is64bit: false,
+			expected: &ModuleData{
+				TextVA:    0x1000,
+				Minpc:     0x4000,
+				Types:     0x2000,
+				ETypes:    0x3000,
+				Typelinks: GoSlice64{Data: 0x5000, Len: 10, Capacity: 20},
+				ITablinks: GoSlice64{Data: 0x6000, Len: 15, Capacity: 25},
+				Rodata:    0x9000,
+				Gofunc:    0xa000,
+				Ftab:      GoSlice64{Data: 0x7000, Len: 20, Capacity: 30},
+			},
+		},
+		{
+			name:    "Go 1.18 64-bit",
+			version: "1.18",
+			is64bit: true,
+			expected: &ModuleData{
+				TextVA:    0x1000,
+				Minpc:     0x4000,
+				Types:     0x2000,
+				ETypes:    0x3000,
+				Typelinks: GoSlice64{Data: 0x5000, Len: 10, Capacity: 20},
+				ITablinks: GoSlice64{Data: 0x6000, Len: 15, Capacity: 25},
+				Rodata:    0x9000,
+				Gofunc:    0xa000,
+				Ftab:      GoSlice64{Data: 0x7000, Len: 20, Capacity: 30},
+			},
+		},
+		{
+			name:    "Go 1.22 64-bit",
+			version: "1.22",
+			is64bit: true,
+			expected: &ModuleData{
+				TextVA:     0x1000,
+				Minpc:      0x4000,
+				Types:      0x2000,
+				ETypes:     0x3000,
+				Typelinks:  GoSlice64{Data: 0x5000, Len: 10, Capacity: 20},
+				ITablinks:  GoSlice64{Data: 0x6000, Len: 15, Capacity: 25},
+				Rodata:     0x9000,
+				Gofunc:     0xa000,
+				Covctrs:    0xb000,
+				Ecovctrs:   0xc000,
+				InitTasks:  GoSlice64{Data: 0xd000, Len: 5, Capacity: 10},
+				Ftab:       GoSlice64{Data: 0x7000, Len: 20, Capacity: 30},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		for _, littleEndian := range []bool{true, false} {
+			endianName := "little-endian"
+			if !littleEndian {
+				endianName = "big-endian"
+			}
+			t.Run(tt.name+"/"+endianName, func(t *testing.T) {
+				// Create test data
+				data := createTestModuleData(tt.version, tt.is64bit, littleEndian)
+
+				// Parse the data
+				md, err := ParseModuleData(data, tt.version, tt.is64bit, littleEndian)
+				if err != nil {
+					t.Fatalf("ParseModuleData failed: %v", err)
+				}
+
+				// Compare with expected values
+				if !reflect.DeepEqual(md, tt.expected) {
+					t.Errorf("got %+v, want %+v", md, tt.expected)
+				}
+			})
+		}
+	}
+}
+
+// TestModuleDataParsingArchitectures exercises ParseModuleDataArch directly
+// across {little,big} x {32,64}, covering the ppc64be/s390x/mips family
+// alongside the little-endian amd64/386 path already covered above.
+func TestModuleDataParsingArchitectures(t *testing.T) {
+	archs := []Architecture{ArchAMD64, Arch386, ArchPPC64, ArchPPC64LE, ArchS390X, ArchMIPS, ArchMIPSLE, ArchMIPS64}
+
+	for _, arch := range archs {
+		t.Run(arch.Name, func(t *testing.T) {
+			data := createTestModuleData("1.22", arch.is64bit(), arch.littleEndian())
+
+			md, err := ParseModuleDataArch(data, "1.22", arch)
+			if err != nil {
+				t.Fatalf("ParseModuleDataArch failed: %v", err)
+			}
+			if md.TextVA != 0x1000 {
+				t.Errorf("TextVA = %x, want %x", md.TextVA, 0x1000)
+			}
+			if md.Typelinks.Len != 10 || md.Typelinks.Capacity != 20 {
+				t.Errorf("Typelinks = %+v, want {Len:10 Capacity:20 ...}", md.Typelinks)
+			}
+		})
+	}
+}
+
+// TestDetectLayout builds a moduledata blob shaped like a realistic Go 1.22
+//64-bit binary (plausible VAs, Minpc close to TextVA) and checks DetectLayout
+// recovers the version/bitness without being told either up front -- the
+// scenario a stripped buildinfo stamp forces on callers.
+func TestDetectLayout(t *testing.T) {
+	const firstFuncEntry = 0x450000
+
+	layout := getLayout("1.22", true)
+	buf := make([]byte, 4096)
+
+	writeAt := func(offset FieldOffset, value uint64) {
+		binary.LittleEndian.PutUint64(buf[offset.Offset:], value)
+	}
+	writeSlice := func(offset FieldOffset, data, length, capacity uint64) {
+		binary.LittleEndian.PutUint64(buf[offset.Offset:], data)
+		binary.LittleEndian.PutUint64(buf[offset.Offset+8:], length)
+		binary.LittleEndian.PutUint64(buf[offset.Offset+16:], capacity)
+	}
+
+	writeAt(layout.Text, firstFuncEntry)
+	writeAt(layout.Minpc, firstFuncEntry)
+	writeAt(layout.Types, 0x490000)
+	writeAt(layout.ETypes, 0x4a0000)
+	writeSlice(layout.Typelinks, 0x4b0000, 5, 10)
+	writeSlice(layout.ITablinks, 0x4c0000, 3, 6)
+	writeAt(layout.Covctrs, 0x4d0000)
+	writeAt(layout.Ecovctrs, 0x4e0000)
+
+	version, is64bit, littleEndian, err := DetectLayout(buf, firstFuncEntry)
+	if err != nil {
+		t.Fatalf("DetectLayout failed: %v", err)
+	}
+	if version != "1.22" || !is64bit || !littleEndian {
+		t.Errorf("DetectLayout = (%q, %v, %v), want (\"1.22\", true, true)", version, is64bit, littleEndian)
+	}
+}
+
+// TestDetectLayoutBigEndian is TestDetectLayout's mirror for a big-endian
+// target (ppc64/s390x/mips): DetectLayout must recover littleEndian=false
+// instead of assuming little-endian and failing to match.
+func TestDetectLayoutBigEndian(t *testing.T) {
+	const firstFuncEntry = 0x450000
+
+	layout := getLayout("1.22", true)
+	buf := make([]byte, 4096)
+
+	writeAt := func(offset FieldOffset, value uint64) {
+		binary.BigEndian.PutUint64(buf[offset.Offset:], value)
+	}
+	writeSlice := func(offset FieldOffset, data, length, capacity uint64) {
+		binary.BigEndian.PutUint64(buf[offset.Offset:], data)
+		binary.BigEndian.PutUint64(buf[offset.Offset+8:], length)
+		binary.BigEndian.PutUint64(buf[offset.Offset+16:], capacity)
+	}
+
+	writeAt(layout.Text, firstFuncEntry)
+	writeAt(layout.Minpc, firstFuncEntry)
+	writeAt(layout.Types, 0x490000)
+	writeAt(layout.ETypes, 0x4a0000)
+	writeSlice(layout.Typelinks, 0x4b0000, 5, 10)
+	writeSlice(layout.ITablinks, 0x4c0000, 3, 6)
+	writeAt(layout.Covctrs, 0x4d0000)
+	writeAt(layout.Ecovctrs, 0x4e0000)
+
+	version, is64bit, littleEndian, err := DetectLayout(buf, firstFuncEntry)
+	if err != nil {
+		t.Fatalf("DetectLayout failed: %v", err)
+	}
+	if version != "1.22" || !is64bit || littleEndian {
+		t.Errorf("DetectLayout = (%q, %v, %v), want (\"1.22\", true, false)", version, is64bit, littleEndian)
+	}
+}
+
+func TestDetectLayoutNoMatch(t *testing.T) {
+	// An all-zero blob can't plausibly match any layout: TextVA will be 0,
+	// which won't equal a non-zero firstFuncEntry.
+	buf := make([]byte, 4096)
+	if _, _, _, err := DetectLayout(buf, 0x450000); err == nil {
+		t.Error("DetectLayout succeeded against data with no plausible TextVA match")
+	}
+}
+
+func TestModuleDataValidation(t *testing.T) {
+	// Create test data for Go 1.22 64-bit
+	data := createTestModuleData("1.22", true, true)
+
+	// Parse the data
+	md, err := ParseModuleData(data, "1.22", true, true)
+	if err != nil {
+		t.Fatalf("ParseModuleData failed: %v", err)
+	}
+
+	const fileSize = 0x10000
+
+	// Test validation with matching first function entry
+	err = ValidateModuleData(md, 0x1000, fileSize) // matches TextVA
+	if err != nil {
+		t.Errorf("Validation failed with matching first function entry: %v", err)
+	}
+
+	// Test validation with non-matching first function entry
+	err = ValidateModuleData(md, 0x5000, fileSize) // doesn't match TextVA
+	if err == nil {
+		t.Error("Validation succeeded with non-matching first function entry")
+	}
+
+	// Test validation with an implausible slice length, as would appear if
+	// the endianness used to parse a big-endian binary's moduledata was
+	// guessed wrong and Typelinks.Len came out byte-swapped.
+	garbled := *md
+	garbled.Typelinks.Len = fileSize * 10
+	garbled.Typelinks.Capacity = fileSize * 10
+	err = ValidateModuleData(&garbled, 0x1000, fileSize)
+	if err == nil {
+		t.Error("Validation succeeded with implausibly large Typelinks length")
+	}
+}
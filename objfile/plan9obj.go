@@ -0,0 +1,135 @@
+package objfile
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Plan 9 a.out magic numbers, one per architecture GoReSym can target via
+// GOOS=plan9 (see cmd/go's supported platform list). Each packs a per-ISA
+// identifier into the low bits of the classic a.out magic; see
+// plan9Architecture for the mapping back to the Architecture values in
+// architecture.go. plan9Magic64 marks the wider header amd64 uses, where
+// Entry is 8 bytes instead of 4.
+const (
+	plan9Magic64    = 0x80000000
+	plan9Magic386   = 4*11*11 + 7
+	plan9MagicAMD64 = (4*26*26 + 7) | plan9Magic64
+	plan9MagicARM   = 4*20*20 + 7
+)
+
+// plan9HeaderSize is the size in bytes of the fixed a.out header: magic,
+// text/data/bss/syms sizes, entry point, and the sp/pc table sizes. On
+// amd64 the entry point is 8 bytes instead of 4, widening the header by 4.
+const plan9HeaderSize = 32
+const plan9HeaderSizeAMD64 = plan9HeaderSize + 4
+
+// plan9Header is the Plan 9 a.out header (always big-endian), enough of it
+// for GoReSym to locate the text and data segments; the sp/pc table sizes
+// it also carries aren't needed here.
+type plan9Header struct {
+	Magic uint32
+	Text  uint32
+	Data  uint32
+	Bss   uint32
+	Syms  uint32
+	Entry uint64
+	Spsz  uint32
+	Pcsz  uint32
+}
+
+// plan9Architecture maps a Plan 9 a.out magic number to the Architecture
+// this package already knows about (see architecture.go). An unrecognized
+// magic returns ok=false so callers can report "not a Plan 9 binary"
+// instead of misinterpreting its pointer size/endianness.
+func plan9Architecture(magic uint32) (Architecture, bool) {
+	switch magic {
+	case plan9MagicAMD64:
+		return ArchAMD64, true
+	case plan9Magic386:
+		return Arch386, true
+	case plan9MagicARM:
+		return ArchARM, true
+	default:
+		return Architecture{}, false
+	}
+}
+
+// Plan9File is a Plan 9 a.out binary split into the regions ParseModuleData
+// scans. Plan 9 a.out has no section table, so there's no standalone
+// .rodata: read-only data lives inside the data segment, and Data doubles
+// as the .rodata-equivalent region.
+type Plan9File struct {
+	Arch Architecture
+	Text []byte
+	Data []byte
+}
+
+// OpenPlan9File parses a Plan 9 a.out header (magic, text/data/bss sizes,
+// entry point, symbol table size) and returns the text and data segments
+// it describes, so ParseModuleData can run against Plan 9 Go binaries the
+// same way it already does against ELF/PE/Mach-O.
+func OpenPlan9File(data []byte) (*Plan9File, error) {
+	if len(data) < plan9HeaderSize {
+		return nil, fmt.Errorf("OpenPlan9File: data too short for a.out header: %d bytes", len(data))
+	}
+
+	magic := binary.BigEndian.Uint32(data[0:4])
+	arch, ok := plan9Architecture(magic)
+	if !ok {
+		return nil, fmt.Errorf("OpenPlan9File: unrecognized Plan 9 a.out magic 0x%x", magic)
+	}
+
+	hdr := plan9Header{
+		Magic: magic,
+		Text:  binary.BigEndian.Uint32(data[4:8]),
+		Data:  binary.BigEndian.Uint32(data[8:12]),
+		Bss:   binary.BigEndian.Uint32(data[12:16]),
+		Syms:  binary.BigEndian.Uint32(data[16:20]),
+	}
+	textOff := uint64(plan9HeaderSize)
+	if magic&plan9Magic64 != 0 {
+		if len(data) < plan9HeaderSizeAMD64 {
+			return nil, fmt.Errorf("OpenPlan9File: data too short for 64-bit a.out header: %d bytes", len(data))
+		}
+		hdr.Entry = binary.BigEndian.Uint64(data[20:28])
+		textOff = plan9HeaderSizeAMD64
+	} else {
+		hdr.Entry = uint64(binary.BigEndian.Uint32(data[20:24]))
+	}
+
+	dataOff := textOff + uint64(hdr.Text)
+	dataEnd := dataOff + uint64(hdr.Data)
+	if dataEnd > uint64(len(data)) {
+		return nil, fmt.Errorf("OpenPlan9File: data segment [%d:%d] exceeds file length %d", dataOff, dataEnd, len(data))
+	}
+
+	return &Plan9File{
+		Arch: arch,
+		Text: data[textOff:dataOff],
+		Data: data[dataOff:dataEnd],
+	}, nil
+}
+
+// ParseModuleDataPlan9 locates the moduledata within a Plan 9 a.out binary
+// and parses it with ParseModuleDataArch, using the Architecture derived
+// from the a.out header for pointer size/endianness. moduledataAt scans
+// the text+data bytes for the raw moduledata -- that's existing
+// pclntab-scanning logic this package doesn't otherwise contain, so
+// callers supply it, same as ParseModuleDataAllSlices does for Mach-O.
+func ParseModuleDataPlan9(data []byte, version string, moduledataAt func(text, data []byte) ([]byte, error)) (*ModuleData, error) {
+	f, err := OpenPlan9File(data)
+	if err != nil {
+		return nil, err
+	}
+
+	mdBytes, err := moduledataAt(f.Text, f.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ParseModuleDataPlan9: %v", err)
+	}
+	md, err := ParseModuleDataArch(mdBytes, version, f.Arch)
+	if err != nil {
+		return nil, fmt.Errorf("ParseModuleDataPlan9: %v", err)
+	}
+	return md, nil
+}
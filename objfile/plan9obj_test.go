@@ -0,0 +1,94 @@
+package objfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// buildPlan9AMD64 assembles a minimal Plan 9 a.out file for amd64 (64-bit
+// entry point) wrapping the given text and data segments.
+func buildPlan9AMD64(text, data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(plan9MagicAMD64))
+	binary.Write(&buf, binary.BigEndian, uint32(len(text)))
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // bss
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // syms
+	binary.Write(&buf, binary.BigEndian, uint64(0)) // entry
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // spsz
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // pcsz
+	buf.Write(text)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// buildPlan9386 assembles a minimal Plan 9 a.out file for 386 (32-bit entry
+// point) wrapping the given text and data segments.
+func buildPlan9386(text, data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(plan9Magic386))
+	binary.Write(&buf, binary.BigEndian, uint32(len(text)))
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // bss
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // syms
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // entry
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // spsz
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // pcsz
+	buf.Write(text)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestOpenPlan9FileAMD64(t *testing.T) {
+	text := bytes.Repeat([]byte{0x90}, 16)
+	data := bytes.Repeat([]byte{0x01}, 8)
+
+	f, err := OpenPlan9File(buildPlan9AMD64(text, data))
+	if err != nil {
+		t.Fatalf("OpenPlan9File failed: %v", err)
+	}
+	if f.Arch.Name != "amd64" {
+		t.Errorf("got arch %q, want amd64", f.Arch.Name)
+	}
+	if !bytes.Equal(f.Text, text) {
+		t.Errorf("got text %x, want %x", f.Text, text)
+	}
+	if !bytes.Equal(f.Data, data) {
+		t.Errorf("got data %x, want %x", f.Data, data)
+	}
+}
+
+func TestOpenPlan9File386(t *testing.T) {
+	text := bytes.Repeat([]byte{0x90}, 4)
+	data := bytes.Repeat([]byte{0x02}, 4)
+
+	f, err := OpenPlan9File(buildPlan9386(text, data))
+	if err != nil {
+		t.Fatalf("OpenPlan9File failed: %v", err)
+	}
+	if f.Arch.Name != "386" {
+		t.Errorf("got arch %q, want 386", f.Arch.Name)
+	}
+	if !bytes.Equal(f.Text, text) {
+		t.Errorf("got text %x, want %x", f.Text, text)
+	}
+	if !bytes.Equal(f.Data, data) {
+		t.Errorf("got data %x, want %x", f.Data, data)
+	}
+}
+
+func TestOpenPlan9FileUnrecognizedMagic(t *testing.T) {
+	data := make([]byte, plan9HeaderSize)
+	binary.BigEndian.PutUint32(data[0:4], 0xdeadbeef)
+
+	if _, err := OpenPlan9File(data); err == nil {
+		t.Fatal("expected error for unrecognized magic, got nil")
+	}
+}
+
+func TestOpenPlan9FileTruncated(t *testing.T) {
+	if _, err := OpenPlan9File(make([]byte, 4)); err == nil {
+		t.Fatal("expected error for truncated header, got nil")
+	}
+}